@@ -0,0 +1,77 @@
+// Package latest defines the API versions this client understands and the
+// per-version behavior (codec, REST path prefix, legacy quirks) other
+// packages key off of when talking to an OpenShift server.
+package latest
+
+import (
+	"fmt"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+)
+
+// Versions lists the API versions this client understands, ordered oldest
+// to newest. Callers that need "the highest version both we and the server
+// support" should walk this slice in order, as NegotiateVersion does.
+var Versions = []string{"v1beta1", "v1beta3", "v1"}
+
+// Version is the preferred API version new clients should use when the
+// server does not support version negotiation.
+var Version = "v1"
+
+// VersionInterfaces holds the behavior needed to talk to the server at a
+// particular API version.
+type VersionInterfaces struct {
+	Codec runtime.Codec
+}
+
+var versionInterfaces = map[string]*VersionInterfaces{
+	"v1beta1": {Codec: runtime.DefaultCodec},
+	"v1beta3": {Codec: runtime.DefaultCodec},
+	"v1":      {Codec: runtime.DefaultCodec},
+}
+
+// InterfacesFor returns the VersionInterfaces for a given API version, or an
+// error if the version isn't one this client understands.
+func InterfacesFor(version string) (*VersionInterfaces, error) {
+	if interfaces, ok := versionInterfaces[version]; ok {
+		return interfaces, nil
+	}
+	return nil, fmt.Errorf("no version interfaces registered for %q", version)
+}
+
+// prefixes maps each registered API version to the REST path prefix the
+// server serves it under. Older versions were served under the original
+// "/osapi" prefix; "v1" and later moved to the shorter "/oapi".
+var prefixes = map[string]string{
+	"v1beta1": "/osapi",
+	"v1beta3": "/osapi",
+	"v1":      "/oapi",
+}
+
+// PrefixFor returns the REST path prefix the server serves version under.
+// Adding support for a new server API version only requires a new entry
+// here, rather than edits scattered across the client.
+func PrefixFor(version string) (string, error) {
+	if prefix, ok := prefixes[version]; ok {
+		return prefix, nil
+	}
+	return "", fmt.Errorf("no REST path prefix registered for API version %q", version)
+}
+
+// legacyBehavior maps each registered API version to whether clients
+// talking at that version need to accommodate pre-v1beta3 REST quirks
+// (e.g. singular resource names, different status field names).
+var legacyBehavior = map[string]bool{
+	"v1beta1": true,
+	"v1beta3": false,
+	"v1":      false,
+}
+
+// LegacyBehaviorFor returns whether version requires the client to
+// accommodate pre-v1beta3 REST quirks.
+func LegacyBehaviorFor(version string) (bool, error) {
+	if legacy, ok := legacyBehavior[version]; ok {
+		return legacy, nil
+	}
+	return false, fmt.Errorf("no legacy behavior registered for API version %q", version)
+}
@@ -0,0 +1,51 @@
+package latest
+
+import "testing"
+
+func TestPrefixForRegisteredVersions(t *testing.T) {
+	expected := map[string]string{
+		"v1beta1": "/osapi",
+		"v1beta3": "/osapi",
+		"v1":      "/oapi",
+	}
+	for _, version := range Versions {
+		prefix, err := PrefixFor(version)
+		if err != nil {
+			t.Errorf("PrefixFor(%q) returned error: %v", version, err)
+			continue
+		}
+		if prefix != expected[version] {
+			t.Errorf("PrefixFor(%q) = %q, want %q", version, prefix, expected[version])
+		}
+	}
+}
+
+func TestPrefixForUnknownVersion(t *testing.T) {
+	if _, err := PrefixFor("v2"); err == nil {
+		t.Fatalf("expected an error for an unregistered version, got nil")
+	}
+}
+
+func TestLegacyBehaviorForRegisteredVersions(t *testing.T) {
+	expected := map[string]bool{
+		"v1beta1": true,
+		"v1beta3": false,
+		"v1":      false,
+	}
+	for _, version := range Versions {
+		legacy, err := LegacyBehaviorFor(version)
+		if err != nil {
+			t.Errorf("LegacyBehaviorFor(%q) returned error: %v", version, err)
+			continue
+		}
+		if legacy != expected[version] {
+			t.Errorf("LegacyBehaviorFor(%q) = %v, want %v", version, legacy, expected[version])
+		}
+	}
+}
+
+func TestLegacyBehaviorForUnknownVersion(t *testing.T) {
+	if _, err := LegacyBehaviorFor("v2"); err == nil {
+		t.Fatalf("expected an error for an unregistered version, got nil")
+	}
+}
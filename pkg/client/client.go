@@ -1,15 +1,20 @@
 package client
 
 import (
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
+	"net/http"
 	"os"
 	"path"
 	"runtime"
 	"strings"
+	"sync"
 
 	kclient "github.com/GoogleCloudPlatform/kubernetes/pkg/client"
 
 	"github.com/openshift/origin/pkg/api/latest"
+	"github.com/openshift/origin/pkg/client/discovery"
 	"github.com/openshift/origin/pkg/version"
 )
 
@@ -132,14 +137,113 @@ func (c *Client) SubjectAccessReviews(namespace string) SubjectAccessReviewInter
 	return newSubjectAccessReviews(c, namespace)
 }
 
+// Discovery returns an interface for querying the server's currently
+// advertised API versions and resources.
+func (c *Client) Discovery() discovery.DiscoveryInterface {
+	return discovery.NewDiscoveryClient(c.RESTClient)
+}
+
+// RESTMapper returns a discovery.RESTMapper built from the resources the
+// server is currently advertising. A successful discovery call is cached, so
+// repeated callers (e.g. one SharedInformerFactory resolving several kinds)
+// don't each re-probe the server's full discovery document; a failed one is
+// not cached, so a transient discovery error (e.g. the server was briefly
+// unreachable) doesn't permanently poison the Client — the next call tries
+// again.
+func (c *Client) RESTMapper() (discovery.RESTMapper, error) {
+	c.restMapperLock.Lock()
+	defer c.restMapperLock.Unlock()
+
+	if c.restMapper != nil {
+		return c.restMapper, nil
+	}
+	group, err := c.Discovery().ServerGroup()
+	if err != nil {
+		return nil, err
+	}
+	c.restMapper = discovery.NewRESTMapper(group)
+	return c.restMapper, nil
+}
+
+// DynamicResourceClient is a REST client pre-scoped to the resource that
+// serves one Kind, so callers don't need to remember to call Resource(...)
+// themselves on every request.
+type DynamicResourceClient struct {
+	client    *kclient.RESTClient
+	resource  string
+	namespace string
+}
+
+func (d *DynamicResourceClient) Get() *kclient.Request {
+	return d.client.Get().Namespace(d.namespace).Resource(d.resource)
+}
+
+func (d *DynamicResourceClient) Post() *kclient.Request {
+	return d.client.Post().Namespace(d.namespace).Resource(d.resource)
+}
+
+func (d *DynamicResourceClient) Put() *kclient.Request {
+	return d.client.Put().Namespace(d.namespace).Resource(d.resource)
+}
+
+func (d *DynamicResourceClient) Delete() *kclient.Request {
+	return d.client.Delete().Namespace(d.namespace).Resource(d.resource)
+}
+
+// DynamicResource returns a REST client scoped to the resource that serves
+// kind at apiVersion, so callers that only know a Kind/APIVersion pair
+// (e.g. parsed from an arbitrary YAML/JSON blob) can CRUD it generically,
+// without a typed accessor like Builds() or Routes(). The mapping is
+// resolved at apiVersion specifically (not whichever version the server
+// happens to serve kind at most recently), so a blob pinned to an older
+// apiVersion still routes correctly even when the server also serves a
+// newer one. An empty namespace is valid even for a namespaced kind: it
+// means "all namespaces", the same convention Builds(""), Routes(""), etc.
+// already follow.
+func (c *Client) DynamicResource(kind, apiVersion, namespace string) (*DynamicResourceClient, error) {
+	mapper, err := c.RESTMapper()
+	if err != nil {
+		return nil, err
+	}
+	mapping, err := mapper.ResourceFor(kind, apiVersion)
+	if err != nil {
+		return nil, err
+	}
+	return &DynamicResourceClient{client: c.RESTClient, resource: mapping.Resource, namespace: namespace}, nil
+}
+
 // Client is an OpenShift client object
 type Client struct {
 	*kclient.RESTClient
+
+	// negotiatedVersion is the API version this client was built for: the
+	// config.Version passed to New, or latest.Version when that was empty.
+	// New does not probe the server to choose it; NewNegotiated does so
+	// before calling New.
+	negotiatedVersion string
+
+	// restMapperLock guards the lazily-discovered, cached RESTMapper below,
+	// so concurrent callers don't each probe the server's discovery
+	// document, and so only a successful discovery result is cached.
+	restMapperLock sync.Mutex
+	restMapper     discovery.RESTMapper
+}
+
+// NegotiatedVersion returns the API version this client was built for: the
+// version NewNegotiated negotiated with the server, the version pinned in
+// the Config passed to New, or latest.Version when New was given no version.
+// New itself never negotiates, so for a client built by New this is not
+// necessarily a version the server supports.
+func (c *Client) NegotiatedVersion() string {
+	return c.negotiatedVersion
 }
 
 // New creates an OpenShift client for the given config. This client works with builds, deployments,
 // templates, routes, and images. It allows operations such as list, get, update and delete on these
-// objects. An error is returned if the provided configuration is not valid.
+// objects. An error is returned if the provided configuration is not valid. New never talks to the
+// network itself: if c.Version is empty, SetOpenShiftDefaults pins it to latest.Version without
+// probing the server. Callers that want a server-negotiated version instead should call
+// NegotiateVersion (or use NewNegotiated) and set config.Version before calling New.
 func New(c *kclient.Config) (*Client, error) {
 	config := *c
 	if err := SetOpenShiftDefaults(&config); err != nil {
@@ -149,19 +253,31 @@ func New(c *kclient.Config) (*Client, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &Client{client}, nil
+	return &Client{RESTClient: client, negotiatedVersion: config.Version}, nil
 }
 
-func SetOpenShiftDefaults(config *kclient.Config) error {
-	if config.Prefix == "" {
-		config.Prefix = "/osapi"
+// NewNegotiated is like New, but if config.Version is empty it negotiates a version with the
+// server (see NegotiateVersion) instead of defaulting to latest.Version, and fails if the server
+// is unreachable. Use this when talking to a server whose supported versions aren't known ahead of
+// time; use New directly when a pinned config.Version (or its implicit latest.Version default) is
+// fine, including any offline construction (e.g. in tests).
+func NewNegotiated(c *kclient.Config) (*Client, error) {
+	config := *c
+	if config.Version == "" {
+		negotiated, err := NegotiateVersion(&config)
+		if err != nil {
+			return nil, err
+		}
+		config.Version = negotiated
 	}
+	return New(&config)
+}
+
+func SetOpenShiftDefaults(config *kclient.Config) error {
 	if len(config.UserAgent) == 0 {
 		config.UserAgent = DefaultOpenShiftUserAgent()
 	}
 	if config.Version == "" {
-		// Clients default to the preferred code API version
-		// TODO: implement version negotiation (highest version supported by server)
 		config.Version = latest.Version
 	}
 	version := config.Version
@@ -169,13 +285,136 @@ func SetOpenShiftDefaults(config *kclient.Config) error {
 	if err != nil {
 		return fmt.Errorf("API version '%s' is not recognized (valid values: %s)", version, strings.Join(latest.Versions, ", "))
 	}
+	if config.Prefix == "" {
+		prefix, err := latest.PrefixFor(version)
+		if err != nil {
+			return err
+		}
+		config.Prefix = prefix
+	}
 	if config.Codec == nil {
 		config.Codec = versionInterfaces.Codec
 	}
-	config.LegacyBehavior = (config.Version == "v1beta1")
+	legacy, err := latest.LegacyBehaviorFor(version)
+	if err != nil {
+		return err
+	}
+	config.LegacyBehavior = legacy
 	return nil
 }
 
+// serverAPIVersions is the subset of the discovery document served at the
+// OpenShift API root (e.g. /osapi) that NegotiateVersion cares about.
+type serverAPIVersions struct {
+	Versions []string `json:"versions"`
+}
+
+// ErrNoVersionOverlap is returned by NegotiateVersion when the server and
+// this client do not share a common API version.
+type ErrNoVersionOverlap struct {
+	ServerVersions []string
+	ClientVersions []string
+}
+
+func (e *ErrNoVersionOverlap) Error() string {
+	return fmt.Sprintf("no common API version between client (%s) and server (%s)",
+		strings.Join(e.ClientVersions, ", "), strings.Join(e.ServerVersions, ", "))
+}
+
+// NegotiateVersion queries the OpenShift server's discovery endpoint(s) for
+// the list of API versions it advertises, intersects that list with the
+// versions this client was built with, and returns the highest mutually
+// supported version. It makes a network round-trip and fails if the server
+// is unreachable, so it is never called implicitly by New/SetOpenShiftDefaults;
+// callers that want a negotiated version must call it (or NewNegotiated)
+// explicitly and opt into that round-trip themselves.
+func NegotiateVersion(config *kclient.Config) (string, error) {
+	transport, err := kclient.TransportFor(config)
+	if err != nil {
+		return "", err
+	}
+	httpClient := &http.Client{Transport: transport}
+
+	var serverVersions serverAPIVersions
+	var lastErr error
+	queried := false
+	for _, prefix := range discoveryPrefixes(config) {
+		versions, err := getServerAPIVersions(httpClient, config.Host+prefix)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		queried = true
+		serverVersions.Versions = append(serverVersions.Versions, versions.Versions...)
+	}
+	if !queried {
+		return "", fmt.Errorf("couldn't negotiate API version with server: %v", lastErr)
+	}
+
+	// latest.Versions is ordered oldest to newest; walk it in order and
+	// keep the last (highest) version the server also advertises.
+	best := ""
+	for _, clientVersion := range latest.Versions {
+		for _, serverVersion := range serverVersions.Versions {
+			if clientVersion == serverVersion {
+				best = clientVersion
+				break
+			}
+		}
+	}
+	if best == "" {
+		return "", &ErrNoVersionOverlap{ServerVersions: serverVersions.Versions, ClientVersions: latest.Versions}
+	}
+	return best, nil
+}
+
+// discoveryPrefixes returns the REST path prefix(es) NegotiateVersion should
+// probe. If the caller pinned config.Prefix, only that prefix is tried.
+// Otherwise every distinct prefix latest knows about is tried (e.g. both
+// "/osapi" and "/oapi"), since the whole point of negotiation is that the
+// client doesn't yet know which API version, and therefore which prefix,
+// the server speaks.
+func discoveryPrefixes(config *kclient.Config) []string {
+	if config.Prefix != "" {
+		return []string{config.Prefix}
+	}
+	seen := map[string]bool{}
+	var prefixes []string
+	for _, version := range latest.Versions {
+		prefix, err := latest.PrefixFor(version)
+		if err != nil || seen[prefix] {
+			continue
+		}
+		seen[prefix] = true
+		prefixes = append(prefixes, prefix)
+	}
+	return prefixes
+}
+
+// getServerAPIVersions GETs url and decodes the discovery document served
+// there, failing on both transport errors and non-200 responses so a 404
+// from a prefix the server doesn't serve is reported clearly rather than as
+// a confusing JSON parse error.
+func getServerAPIVersions(httpClient *http.Client, url string) (*serverAPIVersions, error) {
+	resp, err := httpClient.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't reach %s: %v", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s returned HTTP %d", url, resp.StatusCode)
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't read API versions from %s: %v", url, err)
+	}
+	var versions serverAPIVersions
+	if err := json.Unmarshal(body, &versions); err != nil {
+		return nil, fmt.Errorf("couldn't parse API versions from %s: %v", url, err)
+	}
+	return &versions, nil
+}
+
 // NewOrDie creates an OpenShift client and panics if the provided API version is not recognized.
 func NewOrDie(c *kclient.Config) *Client {
 	client, err := New(c)
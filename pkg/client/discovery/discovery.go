@@ -0,0 +1,213 @@
+// Package discovery lets callers find out, at runtime, which API group,
+// versions and resources an OpenShift server serves, and map a Kind onto
+// the REST resource that serves it.
+package discovery
+
+import (
+	"encoding/json"
+	"fmt"
+
+	kclient "github.com/GoogleCloudPlatform/kubernetes/pkg/client"
+
+	"github.com/openshift/origin/pkg/api/latest"
+)
+
+// APIResource describes a single type the server knows how to serve, at one
+// of its advertised API versions.
+type APIResource struct {
+	// Kind is the Go-style type name, e.g. "Build" or "DeploymentConfig".
+	Kind string
+	// Name is the plural REST resource name, e.g. "builds".
+	Name string
+	// Namespaced is true if the resource is scoped to a namespace.
+	Namespaced bool
+}
+
+// APIGroup describes the versions and resources an OpenShift server
+// advertises. OpenShift currently serves a single, ungrouped API, so this
+// is the whole of what a server can tell a client about itself.
+type APIGroup struct {
+	Versions []string
+	// Resources maps an advertised version to the resources served at it.
+	Resources map[string][]APIResource
+}
+
+// DiscoveryInterface talks to an OpenShift server to find out which API
+// versions and resources it serves.
+type DiscoveryInterface interface {
+	ServerGroup() (*APIGroup, error)
+}
+
+// RESTMapping describes how a Kind maps onto a REST resource.
+type RESTMapping struct {
+	Kind       string
+	Resource   string
+	Version    string
+	Namespaced bool
+}
+
+// RESTMapper maps a Kind to the REST resource that serves it, analogous to
+// meta.KindToResource/mapper.KindFor used for upstream Kubernetes types.
+type RESTMapper interface {
+	// KindFor returns the mapping for kind at the highest version the
+	// server advertises it at.
+	KindFor(kind string) (RESTMapping, error)
+	// ResourceFor returns the mapping for kind at exactly version, so a
+	// caller that already knows which apiVersion it needs (e.g. from a
+	// parsed object) doesn't get routed to a newer version it didn't ask
+	// for.
+	ResourceFor(kind, version string) (RESTMapping, error)
+}
+
+// discoveryClient implements DiscoveryInterface against a live server.
+type discoveryClient struct {
+	client *kclient.RESTClient
+}
+
+// NewDiscoveryClient returns a DiscoveryInterface that queries the server
+// reachable through restClient for its supported versions and resources.
+func NewDiscoveryClient(restClient *kclient.RESTClient) DiscoveryInterface {
+	return &discoveryClient{client: restClient}
+}
+
+// serverAPIVersions mirrors the discovery document served at the API root.
+type serverAPIVersions struct {
+	Versions []string `json:"versions"`
+}
+
+// apiResourceList mirrors the discovery document served at each version's
+// root, e.g. GET /osapi/v1.
+type apiResourceList struct {
+	GroupVersion string        `json:"groupVersion"`
+	Resources    []apiResource `json:"resources"`
+}
+
+type apiResource struct {
+	Name       string `json:"name"`
+	Kind       string `json:"kind"`
+	Namespaced bool   `json:"namespaced"`
+}
+
+// ServerGroup discovers the versions the server advertises by probing each
+// REST path prefix latest knows about (e.g. both "/osapi" and "/oapi"),
+// since which prefix serves the discovery document itself depends on the
+// version served there. It then fetches the resources served at each
+// discovered version from that version's own prefix (via latest.PrefixFor),
+// rather than assuming a single shared root.
+func (d *discoveryClient) ServerGroup() (*APIGroup, error) {
+	found := map[string]bool{}
+	var lastErr error
+	probed := false
+	for _, prefix := range knownPrefixes() {
+		body, err := d.client.Get().AbsPath(prefix).Do().Raw()
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		var versions serverAPIVersions
+		if err := json.Unmarshal(body, &versions); err != nil {
+			lastErr = fmt.Errorf("couldn't parse server API versions from %q: %v", prefix, err)
+			continue
+		}
+		probed = true
+		for _, v := range versions.Versions {
+			found[v] = true
+		}
+	}
+	if !probed {
+		return nil, fmt.Errorf("couldn't read server API versions: %v", lastErr)
+	}
+
+	group := &APIGroup{Resources: map[string][]APIResource{}}
+	for _, version := range latest.Versions {
+		if !found[version] {
+			continue
+		}
+		group.Versions = append(group.Versions, version)
+		resources, err := d.serverResourcesForVersion(version)
+		if err != nil {
+			return nil, err
+		}
+		group.Resources[version] = resources
+	}
+	return group, nil
+}
+
+func (d *discoveryClient) serverResourcesForVersion(version string) ([]APIResource, error) {
+	prefix, err := latest.PrefixFor(version)
+	if err != nil {
+		return nil, err
+	}
+	res, err := d.client.Get().AbsPath(prefix + "/" + version).Do().Raw()
+	if err != nil {
+		return nil, fmt.Errorf("couldn't read resources for API version %q: %v", version, err)
+	}
+	var list apiResourceList
+	if err := json.Unmarshal(res, &list); err != nil {
+		return nil, fmt.Errorf("couldn't parse resources for API version %q: %v", version, err)
+	}
+	resources := make([]APIResource, 0, len(list.Resources))
+	for _, r := range list.Resources {
+		resources = append(resources, APIResource{Kind: r.Kind, Name: r.Name, Namespaced: r.Namespaced})
+	}
+	return resources, nil
+}
+
+// knownPrefixes returns every distinct REST path prefix latest knows about,
+// in the order latest.Versions lists the versions that use them.
+func knownPrefixes() []string {
+	seen := map[string]bool{}
+	var prefixes []string
+	for _, version := range latest.Versions {
+		prefix, err := latest.PrefixFor(version)
+		if err != nil || seen[prefix] {
+			continue
+		}
+		seen[prefix] = true
+		prefixes = append(prefixes, prefix)
+	}
+	return prefixes
+}
+
+// restMapper is a RESTMapper built from a discovered APIGroup.
+type restMapper struct {
+	group *APIGroup
+}
+
+// NewRESTMapper returns a RESTMapper backed by the resources a server
+// advertised in group.
+func NewRESTMapper(group *APIGroup) RESTMapper {
+	return &restMapper{group: group}
+}
+
+// KindFor returns the mapping for kind at the highest version the server
+// advertises it at. m.group.Versions is ordered oldest to newest (the same
+// convention latest.Versions and NegotiateVersion use), so later matches
+// overwrite earlier ones.
+func (m *restMapper) KindFor(kind string) (RESTMapping, error) {
+	found := false
+	var mapping RESTMapping
+	for _, version := range m.group.Versions {
+		for _, resource := range m.group.Resources[version] {
+			if resource.Kind == kind {
+				mapping = RESTMapping{Kind: kind, Resource: resource.Name, Version: version, Namespaced: resource.Namespaced}
+				found = true
+			}
+		}
+	}
+	if !found {
+		return RESTMapping{}, fmt.Errorf("no resource is registered for kind %q", kind)
+	}
+	return mapping, nil
+}
+
+// ResourceFor returns the mapping for kind at exactly version, regardless of
+// whether the server also serves kind at a newer version.
+func (m *restMapper) ResourceFor(kind, version string) (RESTMapping, error) {
+	for _, resource := range m.group.Resources[version] {
+		if resource.Kind == kind {
+			return RESTMapping{Kind: kind, Resource: resource.Name, Version: version, Namespaced: resource.Namespaced}, nil
+		}
+	}
+	return RESTMapping{}, fmt.Errorf("no resource is registered for kind %q at API version %q", kind, version)
+}
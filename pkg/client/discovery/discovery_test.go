@@ -0,0 +1,126 @@
+package discovery
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	kclient "github.com/GoogleCloudPlatform/kubernetes/pkg/client"
+)
+
+func TestKindForPrefersHighestVersion(t *testing.T) {
+	group := &APIGroup{
+		Versions: []string{"v1beta1", "v1beta3", "v1"},
+		Resources: map[string][]APIResource{
+			"v1beta1": {{Kind: "Build", Name: "builds", Namespaced: true}},
+			"v1beta3": {{Kind: "Build", Name: "builds", Namespaced: true}},
+			"v1":      {{Kind: "Build", Name: "builds", Namespaced: true}},
+		},
+	}
+
+	mapping, err := NewRESTMapper(group).KindFor("Build")
+	if err != nil {
+		t.Fatalf("KindFor returned error: %v", err)
+	}
+	if mapping.Version != "v1" {
+		t.Errorf("Version = %q, want %q (the highest version advertising the kind)", mapping.Version, "v1")
+	}
+	if mapping.Resource != "builds" {
+		t.Errorf("Resource = %q, want %q", mapping.Resource, "builds")
+	}
+}
+
+func TestKindForOnlyAdvertisedAtOlderVersion(t *testing.T) {
+	group := &APIGroup{
+		Versions: []string{"v1beta1", "v1"},
+		Resources: map[string][]APIResource{
+			"v1beta1": {{Kind: "ImageRepositoryMapping", Name: "imagerepositorymappings", Namespaced: true}},
+			"v1":      {{Kind: "Build", Name: "builds", Namespaced: true}},
+		},
+	}
+
+	mapping, err := NewRESTMapper(group).KindFor("ImageRepositoryMapping")
+	if err != nil {
+		t.Fatalf("KindFor returned error: %v", err)
+	}
+	if mapping.Version != "v1beta1" {
+		t.Errorf("Version = %q, want %q", mapping.Version, "v1beta1")
+	}
+}
+
+func TestKindForUnknownKind(t *testing.T) {
+	group := &APIGroup{
+		Versions:  []string{"v1"},
+		Resources: map[string][]APIResource{"v1": {{Kind: "Build", Name: "builds"}}},
+	}
+
+	if _, err := NewRESTMapper(group).KindFor("DoesNotExist"); err == nil {
+		t.Fatalf("expected an error for an unregistered kind, got nil")
+	}
+}
+
+func TestResourceForPinnedToOlderVersion(t *testing.T) {
+	group := &APIGroup{
+		Versions: []string{"v1beta1", "v1"},
+		Resources: map[string][]APIResource{
+			"v1beta1": {{Kind: "Build", Name: "builds", Namespaced: true}},
+			"v1":      {{Kind: "Build", Name: "builds", Namespaced: true}},
+		},
+	}
+
+	mapping, err := NewRESTMapper(group).ResourceFor("Build", "v1beta1")
+	if err != nil {
+		t.Fatalf("ResourceFor returned error: %v", err)
+	}
+	if mapping.Version != "v1beta1" {
+		t.Errorf("Version = %q, want %q (the version explicitly requested)", mapping.Version, "v1beta1")
+	}
+}
+
+func TestResourceForUnadvertisedVersion(t *testing.T) {
+	group := &APIGroup{
+		Versions:  []string{"v1"},
+		Resources: map[string][]APIResource{"v1": {{Kind: "Build", Name: "builds"}}},
+	}
+
+	if _, err := NewRESTMapper(group).ResourceFor("Build", "v1beta1"); err == nil {
+		t.Fatalf("expected an error when the kind isn't advertised at the requested version, got nil")
+	}
+}
+
+// TestServerGroupRoundTrip exercises the live HTTP path ServerGroup and
+// serverResourcesForVersion take, against a fake server that only answers
+// requests under the correct "/oapi" and "/oapi/v1" paths — catching any
+// regression back to a path that drops the version's configured prefix.
+func TestServerGroupRoundTrip(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/oapi", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(serverAPIVersions{Versions: []string{"v1"}})
+	})
+	mux.HandleFunc("/oapi/v1", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(apiResourceList{
+			GroupVersion: "v1",
+			Resources:    []apiResource{{Name: "builds", Kind: "Build", Namespaced: true}},
+		})
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	restClient, err := kclient.RESTClientFor(&kclient.Config{Host: server.URL, Version: "v1"})
+	if err != nil {
+		t.Fatalf("couldn't build REST client: %v", err)
+	}
+
+	group, err := NewDiscoveryClient(restClient).ServerGroup()
+	if err != nil {
+		t.Fatalf("ServerGroup returned error: %v", err)
+	}
+	if len(group.Versions) != 1 || group.Versions[0] != "v1" {
+		t.Fatalf("Versions = %v, want [v1]", group.Versions)
+	}
+	resources := group.Resources["v1"]
+	if len(resources) != 1 || resources[0].Kind != "Build" || resources[0].Name != "builds" {
+		t.Fatalf("Resources[v1] = %v, want a single Build/builds entry", resources)
+	}
+}
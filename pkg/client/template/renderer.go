@@ -0,0 +1,233 @@
+// Package template renders OpenShift Templates into concrete lists of
+// objects, with parameter overrides merged client-side from values files
+// and --set-style flags before the populated template is sent to the
+// server for processing. Substitution itself stays server-side so RBAC
+// and parameter validation run on the master, not the client.
+package template
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+
+	"github.com/ghodss/yaml"
+
+	kclient "github.com/GoogleCloudPlatform/kubernetes/pkg/client"
+)
+
+// TemplateRenderRequest describes how to render a template: the template
+// itself, zero or more values files whose parameters are merged in order,
+// and CLI-style --set overrides applied last so they always win.
+type TemplateRenderRequest struct {
+	// TemplateFile is a path to a template on disk, as YAML or JSON.
+	// Ignored if Template is set.
+	TemplateFile string
+	// Template is an in-memory template to render, taking precedence over
+	// TemplateFile when both are set.
+	Template interface{}
+	// ValuesFiles are YAML or JSON files of parameter overrides, merged in
+	// order so a later file wins ties with an earlier one.
+	ValuesFiles []string
+	// SetOverrides are "key=value" pairs applied after every values file,
+	// so they always take precedence over file-provided values.
+	SetOverrides []string
+	// OutputFormat selects how Manifest is encoded ("json" or "yaml").
+	// Defaults to "json".
+	OutputFormat string
+}
+
+// RenderResult is the outcome of rendering a template: the concrete list of
+// objects the server produced, and the raw manifest it sent back.
+type RenderResult struct {
+	List     map[string]interface{}
+	Manifest string
+}
+
+// Renderer renders Templates into concrete object lists, within a single
+// namespace, via the server's template processing endpoint.
+type Renderer struct {
+	client    *kclient.RESTClient
+	namespace string
+}
+
+// NewRenderer returns a Renderer that submits templates through restClient,
+// scoped to namespace.
+func NewRenderer(restClient *kclient.RESTClient, namespace string) *Renderer {
+	return &Renderer{client: restClient, namespace: namespace}
+}
+
+// Render merges req's parameter overrides (lowest precedence first: each
+// values file in order, then each --set override in order) into the
+// template and POSTs the populated template to the server for processing.
+func (r *Renderer) Render(req TemplateRenderRequest) (*RenderResult, error) {
+	populated, err := loadTemplate(req)
+	if err != nil {
+		return nil, err
+	}
+
+	values, err := mergeValues(req.ValuesFiles, req.SetOverrides)
+	if err != nil {
+		return nil, err
+	}
+	applyParameters(populated, values)
+
+	body, err := json.Marshal(populated)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't encode populated template: %v", err)
+	}
+
+	format := req.OutputFormat
+	if format == "" {
+		format = "json"
+	}
+
+	manifest, err := r.client.Post().
+		Namespace(r.namespace).
+		Resource("processedTemplates").
+		Param("output", format).
+		Body(body).
+		Do().
+		Raw()
+	if err != nil {
+		return nil, fmt.Errorf("couldn't render template: %v", err)
+	}
+
+	list, err := decodeManifest(manifest, format)
+	if err != nil {
+		return nil, err
+	}
+	return &RenderResult{List: list, Manifest: string(manifest)}, nil
+}
+
+// decodeManifest parses manifest as JSON or YAML depending on format, so
+// Render can understand a server response in whichever OutputFormat it
+// asked for.
+func decodeManifest(manifest []byte, format string) (map[string]interface{}, error) {
+	data := manifest
+	if format == "yaml" {
+		converted, err := yaml.YAMLToJSON(manifest)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't parse rendered template: %v", err)
+		}
+		data = converted
+	}
+	var list map[string]interface{}
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("couldn't parse rendered template: %v", err)
+	}
+	return list, nil
+}
+
+// loadTemplate returns the template req describes as a generic JSON
+// object, ready to have parameter overrides applied.
+func loadTemplate(req TemplateRenderRequest) (map[string]interface{}, error) {
+	var data []byte
+	switch {
+	case req.Template != nil:
+		encoded, err := json.Marshal(req.Template)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't encode template: %v", err)
+		}
+		data = encoded
+	case req.TemplateFile != "":
+		raw, err := ioutil.ReadFile(req.TemplateFile)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't read template file %q: %v", req.TemplateFile, err)
+		}
+		converted, err := yaml.YAMLToJSON(raw)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't parse template file %q: %v", req.TemplateFile, err)
+		}
+		data = converted
+	default:
+		return nil, fmt.Errorf("one of Template or TemplateFile must be set")
+	}
+
+	var tmpl map[string]interface{}
+	if err := json.Unmarshal(data, &tmpl); err != nil {
+		return nil, fmt.Errorf("couldn't parse template: %v", err)
+	}
+	return tmpl, nil
+}
+
+// mergeValues merges parameter overrides from valuesFiles (in order) and
+// then setOverrides (in order), so later sources win ties with earlier
+// ones — the same last-one-wins precedence a Helm-style values overlay
+// uses.
+func mergeValues(valuesFiles []string, setOverrides []string) (map[string]interface{}, error) {
+	merged := map[string]interface{}{}
+	for _, file := range valuesFiles {
+		fileValues, err := readValuesFile(file)
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range fileValues {
+			merged[k] = v
+		}
+	}
+	for _, override := range setOverrides {
+		parts := strings.SplitN(override, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid --set override %q, expected key=value", override)
+		}
+		merged[parts[0]] = parts[1]
+	}
+	return merged, nil
+}
+
+// readValuesFile parses a Helm-style values file. Values are decoded as
+// arbitrary JSON/YAML scalars (strings, numbers, bools), not just strings,
+// since a values file is free to write "replicas: 3" or "enabled: true".
+func readValuesFile(path string) (map[string]interface{}, error) {
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't read values file %q: %v", path, err)
+	}
+	converted, err := yaml.YAMLToJSON(raw)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't parse values file %q: %v", path, err)
+	}
+	values := map[string]interface{}{}
+	if err := json.Unmarshal(converted, &values); err != nil {
+		return nil, fmt.Errorf("couldn't parse values file %q: %v", path, err)
+	}
+	return values, nil
+}
+
+// applyParameters overwrites the "value" field of each entry in tmpl's
+// "parameters" array whose "name" matches a key in values, stringifying
+// non-string scalars the way a Template's "value" field expects them.
+func applyParameters(tmpl map[string]interface{}, values map[string]interface{}) {
+	params, ok := tmpl["parameters"].([]interface{})
+	if !ok {
+		return
+	}
+	for _, p := range params {
+		param, ok := p.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _ := param["name"].(string)
+		if value, ok := values[name]; ok {
+			param["value"] = stringifyValue(value)
+		}
+	}
+}
+
+// stringifyValue renders a values-file scalar as the plain string a
+// Template parameter's "value" field expects.
+func stringifyValue(v interface{}) string {
+	switch t := v.(type) {
+	case string:
+		return t
+	case bool:
+		return strconv.FormatBool(t)
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64)
+	default:
+		encoded, _ := json.Marshal(t)
+		return string(encoded)
+	}
+}
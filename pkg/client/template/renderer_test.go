@@ -0,0 +1,132 @@
+package template
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func writeTempFile(t *testing.T, contents string) string {
+	f, err := ioutil.TempFile("", "renderer_test")
+	if err != nil {
+		t.Fatalf("couldn't create temp file: %v", err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(contents); err != nil {
+		t.Fatalf("couldn't write temp file: %v", err)
+	}
+	return f.Name()
+}
+
+func TestMergeValuesPrecedence(t *testing.T) {
+	file1 := writeTempFile(t, `{"a":"1","b":"2"}`)
+	defer os.Remove(file1)
+	file2 := writeTempFile(t, "b: 3\nc: 4\n")
+	defer os.Remove(file2)
+
+	merged, err := mergeValues([]string{file1, file2}, []string{"c=5", "d=6"})
+	if err != nil {
+		t.Fatalf("mergeValues returned error: %v", err)
+	}
+
+	// file2 wins its tie with file1 on "b", and --set wins its tie with
+	// file2 on "c"; "a" and "d" are untouched by the later sources.
+	expected := map[string]interface{}{"a": "1", "b": float64(3), "c": "5", "d": "6"}
+	for k, v := range expected {
+		if merged[k] != v {
+			t.Errorf("merged[%q] = %#v, want %#v", k, merged[k], v)
+		}
+	}
+	if len(merged) != len(expected) {
+		t.Errorf("merged = %#v, want exactly %#v", merged, expected)
+	}
+}
+
+func TestReadValuesFileNonStringScalars(t *testing.T) {
+	file := writeTempFile(t, "replicas: 3\nenabled: true\nname: frontend\n")
+	defer os.Remove(file)
+
+	values, err := readValuesFile(file)
+	if err != nil {
+		t.Fatalf("readValuesFile returned error: %v", err)
+	}
+	if values["replicas"] != float64(3) {
+		t.Errorf("replicas = %#v, want 3", values["replicas"])
+	}
+	if values["enabled"] != true {
+		t.Errorf("enabled = %#v, want true", values["enabled"])
+	}
+	if values["name"] != "frontend" {
+		t.Errorf("name = %#v, want \"frontend\"", values["name"])
+	}
+}
+
+func TestApplyParametersStringifiesScalars(t *testing.T) {
+	tmpl := map[string]interface{}{
+		"parameters": []interface{}{
+			map[string]interface{}{"name": "REPLICAS", "value": "1"},
+			map[string]interface{}{"name": "ENABLED", "value": "false"},
+			map[string]interface{}{"name": "NAME", "value": ""},
+		},
+	}
+	values := map[string]interface{}{
+		"REPLICAS": float64(3),
+		"ENABLED":  true,
+		"NAME":     "frontend",
+	}
+
+	applyParameters(tmpl, values)
+
+	params := tmpl["parameters"].([]interface{})
+	got := map[string]string{}
+	for _, p := range params {
+		param := p.(map[string]interface{})
+		got[param["name"].(string)] = param["value"].(string)
+	}
+
+	want := map[string]string{"REPLICAS": "3", "ENABLED": "true", "NAME": "frontend"}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("parameter %q value = %q, want %q", k, got[k], v)
+		}
+	}
+}
+
+func TestLoadTemplateRoundTrip(t *testing.T) {
+	file := writeTempFile(t, `{
+		"kind": "Template",
+		"apiVersion": "v1",
+		"parameters": [{"name": "NAME", "value": "default"}]
+	}`)
+	defer os.Remove(file)
+
+	tmpl, err := loadTemplate(TemplateRenderRequest{TemplateFile: file})
+	if err != nil {
+		t.Fatalf("loadTemplate returned error: %v", err)
+	}
+	if tmpl["kind"] != "Template" {
+		t.Errorf("kind = %#v, want \"Template\"", tmpl["kind"])
+	}
+	params, ok := tmpl["parameters"].([]interface{})
+	if !ok || len(params) != 1 {
+		t.Fatalf("parameters = %#v, want a single-element list", tmpl["parameters"])
+	}
+}
+
+func TestDecodeManifestYAMLAndJSON(t *testing.T) {
+	jsonList, err := decodeManifest([]byte(`{"kind":"List","items":[]}`), "json")
+	if err != nil {
+		t.Fatalf("decodeManifest(json) returned error: %v", err)
+	}
+	if jsonList["kind"] != "List" {
+		t.Errorf("json: kind = %#v, want \"List\"", jsonList["kind"])
+	}
+
+	yamlList, err := decodeManifest([]byte("kind: List\nitems: []\n"), "yaml")
+	if err != nil {
+		t.Fatalf("decodeManifest(yaml) returned error: %v", err)
+	}
+	if yamlList["kind"] != "List" {
+		t.Errorf("yaml: kind = %#v, want \"List\"", yamlList["kind"])
+	}
+}
@@ -0,0 +1,31 @@
+package client
+
+import (
+	"golang.org/x/net/context"
+
+	"github.com/openshift/origin/pkg/client/template"
+)
+
+// TemplateInterface exposes methods for working with Templates in a namespace.
+type TemplateInterface interface {
+	// Render merges req's parameter overrides into the template and submits
+	// the populated template to the server for processing, returning the
+	// concrete list of objects the server produced. ctx is accepted but not
+	// used: it is not passed to the underlying REST request, so cancelling
+	// it does not cancel the render.
+	Render(ctx context.Context, req template.TemplateRenderRequest) (*template.RenderResult, error)
+}
+
+// templates implements TemplateInterface, scoped to a single namespace.
+type templates struct {
+	r  *Client
+	ns string
+}
+
+func newTemplates(c *Client, namespace string) *templates {
+	return &templates{r: c, ns: namespace}
+}
+
+func (c *templates) Render(ctx context.Context, req template.TemplateRenderRequest) (*template.RenderResult, error) {
+	return template.NewRenderer(c.r.RESTClient, c.ns).Render(req)
+}
@@ -0,0 +1,107 @@
+// Package framework provides a small, reusable controller loop — an
+// informer feeding a rate-limited workqueue, drained by worker goroutines
+// that call a per-controller sync function — so OpenShift controllers (and
+// third-party operators built against client.Client) don't have to
+// reimplement the watch/retry/backoff machinery themselves. The shape
+// (informer -> rate-limited workqueue -> syncHandler) follows the
+// informer/workqueue controller pattern used throughout
+// k8s.io/kubernetes/pkg/controller, not any existing OpenShift controller.
+package framework
+
+import (
+	"time"
+
+	"github.com/golang/glog"
+
+	kcache "github.com/GoogleCloudPlatform/kubernetes/pkg/client/cache"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util/wait"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util/workqueue"
+)
+
+// SyncHandler reconciles the object identified by key (a "namespace/name",
+// or just "name" for cluster-scoped resources). Returning an error causes
+// key to be retried with backoff.
+type SyncHandler func(key string) error
+
+// Controller runs a single informer/workqueue loop: it watches one resource
+// type for changes, enqueues the changed object's key, and calls syncFn for
+// each key a worker goroutine pops off the queue.
+type Controller struct {
+	name     string
+	informer kcache.SharedIndexInformer
+	queue    workqueue.RateLimitingInterface
+	syncFn   SyncHandler
+}
+
+// NewController returns a Controller named name that watches informer and
+// calls syncFn to reconcile each object informer reports changed. Call Run
+// to start processing; NewController only wires up the event handlers.
+func NewController(name string, informer kcache.SharedIndexInformer, syncFn SyncHandler) *Controller {
+	c := &Controller{
+		name:     name,
+		informer: informer,
+		queue:    workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()),
+		syncFn:   syncFn,
+	}
+
+	informer.AddEventHandler(kcache.ResourceEventHandlerFuncs{
+		AddFunc:    c.enqueue,
+		UpdateFunc: func(oldObj, newObj interface{}) { c.enqueue(newObj) },
+		DeleteFunc: c.enqueue,
+	})
+
+	return c
+}
+
+func (c *Controller) enqueue(obj interface{}) {
+	key, err := kcache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		glog.V(4).Infof("%s: couldn't get key for object %+v: %v", c.name, obj, err)
+		return
+	}
+	c.queue.Add(key)
+}
+
+// Run starts workers goroutines draining the queue and blocks until stopCh
+// is closed, then shuts the queue down and returns; it does not wait for
+// the worker goroutines to exit.
+func (c *Controller) Run(workers int, stopCh <-chan struct{}) {
+	defer c.queue.ShutDown()
+
+	glog.Infof("starting %s controller", c.name)
+	defer glog.Infof("shutting down %s controller", c.name)
+
+	go c.informer.Run(stopCh)
+
+	if !kcache.WaitForCacheSync(stopCh, c.informer.HasSynced) {
+		glog.Errorf("%s: timed out waiting for informer cache to sync", c.name)
+		return
+	}
+
+	for i := 0; i < workers; i++ {
+		go wait.Until(c.runWorker, time.Second, stopCh)
+	}
+
+	<-stopCh
+}
+
+func (c *Controller) runWorker() {
+	for c.processNextWorkItem() {
+	}
+}
+
+func (c *Controller) processNextWorkItem() bool {
+	key, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	defer c.queue.Done(key)
+
+	if err := c.syncFn(key.(string)); err != nil {
+		glog.Errorf("%s: error syncing %q, retrying: %v", c.name, key, err)
+		c.queue.AddRateLimited(key)
+		return true
+	}
+	c.queue.Forget(key)
+	return true
+}
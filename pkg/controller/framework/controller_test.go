@@ -0,0 +1,76 @@
+package framework
+
+import (
+	"fmt"
+	"testing"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	kcache "github.com/GoogleCloudPlatform/kubernetes/pkg/client/cache"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
+)
+
+// newTestController builds a Controller around a fake list/watch (standing
+// in for a live server) so processNextWorkItem can be exercised without a
+// real informer ever syncing.
+func newTestController(syncFn SyncHandler) *Controller {
+	informer := kcache.NewSharedIndexInformer(
+		&kcache.ListWatch{
+			ListFunc: func(options kapi.ListOptions) (runtime.Object, error) {
+				return &kapi.List{}, nil
+			},
+			WatchFunc: func(options kapi.ListOptions) (watch.Interface, error) {
+				return watch.NewFake(), nil
+			},
+		},
+		nil,
+		0,
+		kcache.Indexers{},
+	)
+	return NewController("test", informer, syncFn)
+}
+
+func TestProcessNextWorkItemRetriesOnError(t *testing.T) {
+	calls := 0
+	c := newTestController(func(key string) error {
+		calls++
+		return fmt.Errorf("synthetic failure")
+	})
+
+	c.queue.Add("default/foo")
+	if !c.processNextWorkItem() {
+		t.Fatalf("expected processNextWorkItem to return true after a sync error")
+	}
+	if calls != 1 {
+		t.Fatalf("expected syncFn to be called once, got %d", calls)
+	}
+	// AddRateLimited defers the retry, so the key isn't immediately back
+	// in the queue, but it also isn't forgotten: NumRequeues should be > 0.
+	if n := c.queue.NumRequeues("default/foo"); n == 0 {
+		t.Fatalf("expected the failed key to be queued for a rate-limited retry, got NumRequeues = %d", n)
+	}
+}
+
+func TestProcessNextWorkItemForgetsOnSuccess(t *testing.T) {
+	c := newTestController(func(key string) error { return nil })
+
+	c.queue.Add("default/foo")
+	if !c.processNextWorkItem() {
+		t.Fatalf("expected processNextWorkItem to return true")
+	}
+	if n := c.queue.NumRequeues("default/foo"); n != 0 {
+		t.Fatalf("expected a successful sync to forget the key's retry count, got NumRequeues = %d", n)
+	}
+	if c.queue.Len() != 0 {
+		t.Fatalf("expected the queue to be empty after a successful sync, got %d items", c.queue.Len())
+	}
+}
+
+func TestProcessNextWorkItemStopsWhenQueueShutDown(t *testing.T) {
+	c := newTestController(func(key string) error { return nil })
+	c.queue.ShutDown()
+
+	if c.processNextWorkItem() {
+		t.Fatalf("expected processNextWorkItem to return false once the queue is shut down")
+	}
+}
@@ -0,0 +1,100 @@
+package framework
+
+import (
+	"sync"
+	"time"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	kcache "github.com/GoogleCloudPlatform/kubernetes/pkg/client/cache"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
+
+	oclient "github.com/openshift/origin/pkg/client"
+)
+
+// SharedInformerFactory builds SharedIndexInformers for OpenShift resources
+// off a single client.Client, so controllers watching the same resource in
+// the same namespace share one underlying list/watch and cache instead of
+// each opening their own.
+//
+// It lists/watches generically through Client.DynamicResource rather than a
+// hand-written accessor per resource type, so a new controller for
+// BuildConfigs, DeploymentConfigs, ImageRepositories, Routes,
+// PolicyBindings, or any other kind the server advertises needs no new code
+// here — only an entry in the server's discovery document.
+type SharedInformerFactory struct {
+	client       *oclient.Client
+	namespace    string
+	resyncPeriod time.Duration
+
+	// lock guards informers: a shared factory is expected to be asked for
+	// informers concurrently by more than one controller's goroutines.
+	lock      sync.Mutex
+	informers map[string]kcache.SharedIndexInformer
+}
+
+// NewSharedInformerFactory returns a factory that lists/watches resources in
+// namespace (kapi.NamespaceAll for every namespace) through client,
+// resyncing each informer's local cache every resyncPeriod.
+func NewSharedInformerFactory(client *oclient.Client, namespace string, resyncPeriod time.Duration) *SharedInformerFactory {
+	return &SharedInformerFactory{
+		client:       client,
+		namespace:    namespace,
+		resyncPeriod: resyncPeriod,
+		informers:    map[string]kcache.SharedIndexInformer{},
+	}
+}
+
+// InformerFor returns the shared informer for kind, creating it (and its
+// underlying list/watch against the server) the first time it's asked for.
+// Safe to call concurrently.
+func (f *SharedInformerFactory) InformerFor(kind string) (kcache.SharedIndexInformer, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if informer, ok := f.informers[kind]; ok {
+		return informer, nil
+	}
+
+	mapper, err := f.client.RESTMapper()
+	if err != nil {
+		return nil, err
+	}
+	mapping, err := mapper.KindFor(kind)
+	if err != nil {
+		return nil, err
+	}
+	resource, err := f.client.DynamicResource(mapping.Kind, mapping.Version, f.namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	listWatch := &kcache.ListWatch{
+		ListFunc: func(options kapi.ListOptions) (runtime.Object, error) {
+			return resource.Get().Do().Get()
+		},
+		WatchFunc: func(options kapi.ListOptions) (watch.Interface, error) {
+			return resource.Get().Param("resourceVersion", options.ResourceVersion).Watch()
+		},
+	}
+
+	informer := kcache.NewSharedIndexInformer(
+		listWatch,
+		nil,
+		f.resyncPeriod,
+		kcache.Indexers{kcache.NamespaceIndex: kcache.MetaNamespaceIndexFunc},
+	)
+	f.informers[kind] = informer
+	return informer, nil
+}
+
+// NewControllerFor is a convenience that looks up (or creates) the shared
+// informer for kind and wraps it in a Controller named name that calls
+// syncFn for each changed object.
+func (f *SharedInformerFactory) NewControllerFor(name, kind string, syncFn SyncHandler) (*Controller, error) {
+	informer, err := f.InformerFor(kind)
+	if err != nil {
+		return nil, err
+	}
+	return NewController(name, informer, syncFn), nil
+}
@@ -0,0 +1,63 @@
+package framework
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	kcache "github.com/GoogleCloudPlatform/kubernetes/pkg/client/cache"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
+)
+
+// TestInformerForCacheHitIsConcurrencySafe exercises the cache-hit path of
+// InformerFor (the one that doesn't need a live server) from many
+// goroutines at once, so a race detector run catches an unguarded read of
+// the shared informers map.
+func TestInformerForCacheHitIsConcurrencySafe(t *testing.T) {
+	informer := kcache.NewSharedIndexInformer(
+		&kcache.ListWatch{
+			ListFunc: func(options kapi.ListOptions) (runtime.Object, error) {
+				return &kapi.List{}, nil
+			},
+			WatchFunc: func(options kapi.ListOptions) (watch.Interface, error) {
+				return watch.NewFake(), nil
+			},
+		},
+		nil,
+		0,
+		kcache.Indexers{},
+	)
+
+	f := &SharedInformerFactory{
+		namespace:    "default",
+		resyncPeriod: time.Minute,
+		informers:    map[string]kcache.SharedIndexInformer{"Build": informer},
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 50)
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			got, err := f.InformerFor("Build")
+			if err != nil {
+				errs <- err
+				return
+			}
+			if got != informer {
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("InformerFor returned error: %v", err)
+		}
+		t.Fatalf("InformerFor returned an informer other than the cached one")
+	}
+}